@@ -1,8 +1,10 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/michael-freling/claude-code-config/internal/generator"
 	"github.com/spf13/cobra"
@@ -15,75 +17,293 @@ func main() {
 }
 
 func newRootCmd() *cobra.Command {
+	var chroot string
+
 	rootCmd := &cobra.Command{
 		Use:   "generator",
 		Short: "Generate Claude Code prompts for skills, agents, and commands",
 		Long:  `A CLI tool to generate Claude Code prompts from templates for skills, agents, and commands.`,
 	}
+	rootCmd.PersistentFlags().StringVar(&chroot, "chroot", "", "sandbox all file writes beneath this directory")
+
+	newConfig := func() *generator.Config {
+		return &generator.Config{ChrootDir: chroot}
+	}
 
-	rootCmd.AddCommand(newAgentsCmd())
-	rootCmd.AddCommand(newCommandsCmd())
-	rootCmd.AddCommand(newSkillsCmd())
+	rootCmd.AddCommand(newItemCmd(generator.ItemTypeAgent, "agents", "agent", newConfig))
+	rootCmd.AddCommand(newItemCmd(generator.ItemTypeCommand, "commands", "command", newConfig))
+	rootCmd.AddCommand(newItemCmd(generator.ItemTypeSkill, "skills", "skill", newConfig))
 
 	return rootCmd
 }
 
-func newAgentsCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "agents",
-		Short: "Generate prompts for all agents",
-		Long:  `Generate prompts to create all agent definitions.`,
+// newItemCmd builds the parent command for an item type (e.g. "skills"),
+// generating all templates of that type by default, plus "list" and
+// "generate" subcommands.
+func newItemCmd(itemType generator.ItemType, use, singular string, newConfig func() *generator.Config) *cobra.Command {
+	var force bool
+	var noClobber bool
+	var prompt bool
+	var showDiff bool
+	var extraTags []string
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: fmt.Sprintf("Generate prompts for all %s", use),
+		Long:  fmt.Sprintf("Generate prompts to create all %s definitions.", use),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			gen, err := generator.NewGenerator()
+			config := newConfig()
+			policy, err := conflictPolicyFromFlags(force, noClobber, prompt)
+			if err != nil {
+				return err
+			}
+			config.ConflictPolicy = policy
+			config.ShowDiff = showDiff
+
+			gen, err := generator.NewGenerator(config)
 			if err != nil {
 				return fmt.Errorf("failed to create generator: %w", err)
 			}
 
-			if err := gen.GenerateAll(generator.ItemTypeAgent); err != nil {
-				return fmt.Errorf("failed to generate agents: %w", err)
+			if err := gen.GenerateAll(itemType, resolveTags(extraTags)); err != nil {
+				return fmt.Errorf("failed to generate %s: %w", use, err)
 			}
 
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite existing files without prompting")
+	cmd.Flags().BoolVar(&noClobber, "no-clobber", false, "skip existing files instead of overwriting them")
+	cmd.Flags().BoolVar(&prompt, "prompt", false, "ask interactively before overwriting an existing file")
+	cmd.Flags().BoolVar(&showDiff, "diff", false, "print a unified diff against existing files before applying the conflict policy")
+	cmd.Flags().StringArrayVar(&extraTags, "tag", nil, "activate a tag for \"when:\" template constraints, e.g. --tag go --tag linux (in addition to auto-detected tags)")
+
+	cmd.AddCommand(newListCmd(itemType, use, newConfig))
+	cmd.AddCommand(newGenerateCmd(itemType, singular, newConfig))
+	cmd.AddCommand(newNewCmd(itemType, singular, newConfig))
+
+	return cmd
+}
+
+// resolveTags merges the project's auto-detected tags with any explicitly
+// passed via --tag, which take precedence.
+func resolveTags(extra []string) map[string]bool {
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = "."
+	}
+
+	tags := generator.DetectTags(cwd)
+	for _, tag := range extra {
+		tags[tag] = true
+	}
+	return tags
 }
 
-func newCommandsCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "commands",
-		Short: "Generate prompts for all commands",
-		Long:  `Generate prompts to create all command definitions.`,
+// newNewCmd builds the "new" subcommand, which scaffolds a custom template
+// under the user or project template root.
+func newNewCmd(itemType generator.ItemType, singular string, newConfig func() *generator.Config) *cobra.Command {
+	var from string
+	var force bool
+	var project bool
+
+	cmd := &cobra.Command{
+		Use:   "new <name>",
+		Short: fmt.Sprintf("Scaffold a new custom %s template", singular),
+		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			gen, err := generator.NewGenerator()
+			name := args[0]
+
+			gen, err := generator.NewGenerator(newConfig())
 			if err != nil {
 				return fmt.Errorf("failed to create generator: %w", err)
 			}
 
-			if err := gen.GenerateAll(generator.ItemTypeCommand); err != nil {
-				return fmt.Errorf("failed to generate commands: %w", err)
+			if err := gen.NewTemplate(itemType, name, project, from, force); err != nil {
+				return fmt.Errorf("failed to scaffold %s: %w", singular, err)
 			}
 
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&from, "from", "", "fork an existing installed template instead of starting from a blank one")
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite an existing custom template")
+	cmd.Flags().BoolVar(&project, "project", false, "scaffold under the project template root instead of the user one")
+
+	return cmd
 }
 
-func newSkillsCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "skills",
-		Short: "Generate prompts for all skills",
-		Long:  `Generate prompts to create all skill definitions.`,
+// conflictPolicyFromFlags maps the --force/--no-clobber/--prompt flags to a
+// ConflictPolicy. With none of the three, it returns the zero value, which
+// Config documents as behaving like ConflictPolicyOverwrite, so scripted,
+// non-interactive invocations keep regenerating existing files by default.
+func conflictPolicyFromFlags(force, noClobber, prompt bool) (generator.ConflictPolicy, error) {
+	set := 0
+	for _, f := range []bool{force, noClobber, prompt} {
+		if f {
+			set++
+		}
+	}
+	if set > 1 {
+		return "", fmt.Errorf("--force, --no-clobber, and --prompt are mutually exclusive")
+	}
+
+	switch {
+	case force:
+		return generator.ConflictPolicyOverwrite, nil
+	case noClobber:
+		return generator.ConflictPolicySkip, nil
+	case prompt:
+		return generator.ConflictPolicyPrompt, nil
+	default:
+		return "", nil
+	}
+}
+
+// newListCmd builds the "list" subcommand, which shows each template's name
+// and whether it's built-in or a user/project-provided override.
+func newListCmd(itemType generator.ItemType, use string, newConfig func() *generator.Config) *cobra.Command {
+	var showAll bool
+	var extraTags []string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: fmt.Sprintf("List available %s templates", use),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			gen, err := generator.NewGenerator()
+			gen, err := generator.NewGenerator(newConfig())
 			if err != nil {
 				return fmt.Errorf("failed to create generator: %w", err)
 			}
 
-			if err := gen.GenerateAll(generator.ItemTypeSkill); err != nil {
-				return fmt.Errorf("failed to generate skills: %w", err)
+			for _, info := range gen.List(itemType, resolveTags(extraTags)) {
+				if !info.Matches && !showAll {
+					continue
+				}
+
+				status := ""
+				if !info.Matches {
+					status = ", inactive"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%s\t(%s%s)\n", info.Name, info.Source, status)
 			}
 
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&showAll, "all", false, "also show templates whose \"when:\" constraint doesn't match the active tags")
+	cmd.Flags().StringArrayVar(&extraTags, "tag", nil, "activate a tag for \"when:\" template constraints, in addition to auto-detected tags")
+
+	return cmd
+}
+
+// newGenerateCmd builds the "generate" subcommand, which renders a single
+// named template with parameter values supplied via --set, --params-file,
+// or interactive prompts.
+func newGenerateCmd(itemType generator.ItemType, singular string, newConfig func() *generator.Config) *cobra.Command {
+	var setValues map[string]string
+	var paramsFile string
+	var interactive bool
+	var force bool
+	var noClobber bool
+	var prompt bool
+	var showDiff bool
+
+	cmd := &cobra.Command{
+		Use:   "generate <name>",
+		Short: fmt.Sprintf("Generate a single %s from a template", singular),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			config := newConfig()
+			policy, err := conflictPolicyFromFlags(force, noClobber, prompt)
+			if err != nil {
+				return err
+			}
+			config.ConflictPolicy = policy
+			config.ShowDiff = showDiff
+
+			gen, err := generator.NewGenerator(config)
+			if err != nil {
+				return fmt.Errorf("failed to create generator: %w", err)
+			}
+
+			values := map[string]any{}
+			if paramsFile != "" {
+				fileValues, err := generator.LoadParamsFile(paramsFile)
+				if err != nil {
+					return fmt.Errorf("failed to load params file: %w", err)
+				}
+				for k, v := range fileValues {
+					values[k] = v
+				}
+			}
+			for k, v := range setValues {
+				values[k] = v
+			}
+
+			if interactive {
+				if err := promptMissingParams(cmd, gen.Schema(itemType, name), values); err != nil {
+					return fmt.Errorf("failed to read parameters: %w", err)
+				}
+			}
+
+			if err := gen.GenerateWithParams(itemType, name, values); err != nil {
+				return fmt.Errorf("failed to generate %s: %w", singular, err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringToStringVar(&setValues, "set", nil, "set a template parameter, e.g. --set language=go")
+	cmd.Flags().StringVar(&paramsFile, "params-file", "", "YAML file of parameter values")
+	cmd.Flags().BoolVar(&interactive, "interactive", false, "prompt for any missing required parameters")
+	cmd.Flags().BoolVar(&force, "force", false, "overwrite an existing file without prompting")
+	cmd.Flags().BoolVar(&noClobber, "no-clobber", false, "skip instead of overwriting if the file already exists")
+	cmd.Flags().BoolVar(&prompt, "prompt", false, "ask interactively before overwriting an existing file")
+	cmd.Flags().BoolVar(&showDiff, "diff", false, "print a unified diff against the existing file before applying the conflict policy")
+
+	return cmd
+}
+
+// promptMissingParams reads any required parameter not already present in
+// values from stdin, pre-filling the schema's default when one exists.
+func promptMissingParams(cmd *cobra.Command, schema *generator.Schema, values map[string]any) error {
+	if schema == nil {
+		return nil
+	}
+
+	scanner := bufio.NewScanner(cmd.InOrStdin())
+	for _, p := range schema.Parameters {
+		if _, ok := values[p.Name]; ok {
+			continue
+		}
+		if !p.Required && p.Default == nil {
+			continue
+		}
+
+		prompt := p.Name
+		if p.Description != "" {
+			prompt = fmt.Sprintf("%s (%s)", p.Name, p.Description)
+		}
+		if p.Default != nil {
+			prompt = fmt.Sprintf("%s [%v]", prompt, p.Default)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: ", prompt)
+
+		if !scanner.Scan() {
+			break
+		}
+		answer := strings.TrimSpace(scanner.Text())
+		if answer == "" {
+			continue
+		}
+		values[p.Name] = answer
+	}
+
+	return nil
 }
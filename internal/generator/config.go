@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// defaultUserTemplatesDir is the per-user custom template root, relative
+	// to the user's home directory.
+	defaultUserTemplatesDir = "~/.claude/templates"
+	// defaultProjectTemplatesDir is the per-project custom template root,
+	// relative to the current working directory.
+	defaultProjectTemplatesDir = "./.claude/templates"
+
+	// envUserTemplatesDir overrides the per-user template root.
+	envUserTemplatesDir = "CLAUDE_TEMPLATES_DIR"
+	// envProjectTemplatesDir overrides the per-project template root.
+	envProjectTemplatesDir = "CLAUDE_PROJECT_TEMPLATES_DIR"
+)
+
+// Config holds configuration for the Writer and Engine.
+type Config struct {
+	OutputDir string // Base output directory (default: ~/.claude)
+	DryRun    bool   // If true, write to stdout instead of files
+
+	// UserTemplatesDir is the per-user custom template root. If empty, it
+	// defaults to the CLAUDE_TEMPLATES_DIR env var, falling back to
+	// ~/.claude/templates.
+	UserTemplatesDir string
+	// ProjectTemplatesDir is the per-project custom template root. If empty,
+	// it defaults to the CLAUDE_PROJECT_TEMPLATES_DIR env var, falling back
+	// to ./.claude/templates.
+	ProjectTemplatesDir string
+
+	// ChrootDir, if set, sandboxes all file writes beneath this directory via
+	// afero.BasePathFs, regardless of OutputDir. Intended for the CLI's
+	// --chroot flag.
+	ChrootDir string
+
+	// ConflictPolicy controls what happens when a generated file's target
+	// path already exists. The zero value behaves like ConflictPolicyOverwrite.
+	ConflictPolicy ConflictPolicy
+	// ShowDiff, if true, prints a unified diff against the existing file
+	// before applying ConflictPolicy, regardless of which policy is set.
+	ShowDiff bool
+}
+
+// resolveTemplatesDir applies the configured value, falling back to the env
+// var and then the default, in that order of precedence.
+func resolveTemplatesDir(configured, envVar, defaultDir string) (string, error) {
+	dir := configured
+	if dir == "" {
+		dir = os.Getenv(envVar)
+	}
+	if dir == "" {
+		dir = defaultDir
+	}
+	return expandHomeDir(dir)
+}
+
+// resolvedUserTemplatesDir returns c.UserTemplatesDir after applying the
+// CLAUDE_TEMPLATES_DIR env var and default fallbacks.
+func (c *Config) resolvedUserTemplatesDir() (string, error) {
+	return resolveTemplatesDir(c.UserTemplatesDir, envUserTemplatesDir, defaultUserTemplatesDir)
+}
+
+// resolvedProjectTemplatesDir returns c.ProjectTemplatesDir after applying
+// the CLAUDE_PROJECT_TEMPLATES_DIR env var and default fallbacks.
+func (c *Config) resolvedProjectTemplatesDir() (string, error) {
+	return resolveTemplatesDir(c.ProjectTemplatesDir, envProjectTemplatesDir, defaultProjectTemplatesDir)
+}
+
+// expandHomeDir expands ~ to the user's home directory if present at the start.
+func expandHomeDir(p string) (string, error) {
+	if !strings.HasPrefix(p, "~") {
+		return p, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	if p == "~" {
+		return homeDir, nil
+	}
+
+	if strings.HasPrefix(p, "~/") {
+		return filepath.Join(homeDir, p[2:]), nil
+	}
+
+	return p, nil
+}
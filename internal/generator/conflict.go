@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// ConflictPolicy controls what Writer.Write does when the destination file
+// already exists.
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyOverwrite replaces the existing file. This is the
+	// default, matching the tool's original behavior.
+	ConflictPolicyOverwrite ConflictPolicy = "overwrite"
+	// ConflictPolicySkip leaves the existing file untouched.
+	ConflictPolicySkip ConflictPolicy = "skip"
+	// ConflictPolicyFail returns an error instead of touching the file.
+	ConflictPolicyFail ConflictPolicy = "fail"
+	// ConflictPolicyBackup renames the existing file to
+	// "<name>.bak.<timestamp>" before writing the new content.
+	ConflictPolicyBackup ConflictPolicy = "backup"
+	// ConflictPolicyPrompt asks interactively: yes/no/diff/all/abort.
+	ConflictPolicyPrompt ConflictPolicy = "prompt"
+)
+
+// ErrAborted is returned when the user aborts an interactive conflict
+// prompt.
+var ErrAborted = errors.New("aborted by user")
+
+type conflictAction int
+
+const (
+	conflictActionOverwrite conflictAction = iota
+	conflictActionOverwriteAll
+	conflictActionSkip
+	conflictActionAbort
+)
+
+// printUnifiedDiff writes a unified diff between the existing and new
+// content of path to out.
+func printUnifiedDiff(out io.Writer, path, existing, updated string) error {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(existing),
+		B:        difflib.SplitLines(updated),
+		FromFile: path,
+		ToFile:   path + " (generated)",
+		Context:  3,
+	}
+
+	text, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		return fmt.Errorf("failed to compute diff for %s: %w", path, err)
+	}
+
+	fmt.Fprint(out, text)
+	return nil
+}
+
+// promptConflict asks the user how to handle an existing file, showing the
+// diff on demand. It loops until a valid choice or EOF is given.
+func promptConflict(in io.Reader, out io.Writer, path, existing, updated string) (conflictAction, error) {
+	scanner := bufio.NewScanner(in)
+
+	for {
+		fmt.Fprintf(out, "%s already exists. Overwrite? [y]es/[n]o/[d]iff/[a]ll/a[b]ort: ", path)
+
+		if !scanner.Scan() {
+			return conflictActionAbort, nil
+		}
+
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "y", "yes":
+			return conflictActionOverwrite, nil
+		case "n", "no":
+			return conflictActionSkip, nil
+		case "d", "diff":
+			if err := printUnifiedDiff(out, path, existing, updated); err != nil {
+				return conflictActionAbort, err
+			}
+		case "a", "all":
+			return conflictActionOverwriteAll, nil
+		case "b", "abort":
+			return conflictActionAbort, nil
+		default:
+			fmt.Fprintln(out, "please answer y, n, d, a, or b")
+		}
+	}
+}
+
+// backupPath returns the name an existing file is renamed to under
+// ConflictPolicyBackup.
+func backupPath(path string, now time.Time) string {
+	return fmt.Sprintf("%s.bak.%s", path, now.Format("20060102150405"))
+}
@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// whenLineRe matches a "when: <expr>" line inside a template's front matter.
+var whenLineRe = regexp.MustCompile(`(?m)^when:\s*(.*)$`)
+
+// extractWhenConstraint pulls the "when:" key out of content's leading
+// front-matter block (delimited by "---" lines), if present, returning the
+// content with that line removed (so it never leaks into rendered output)
+// and the raw constraint expression. Content without a "when:" line, or
+// without front matter at all, is returned unchanged with an empty
+// expression.
+func extractWhenConstraint(content string) (body string, when string) {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return content, ""
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+	if end == -1 {
+		return content, ""
+	}
+
+	kept := lines[:1]
+	for _, line := range lines[1:end] {
+		if m := whenLineRe.FindStringSubmatch(line); m != nil {
+			when = strings.Trim(strings.TrimSpace(m[1]), `"'`)
+			continue
+		}
+		kept = append(kept, line)
+	}
+	kept = append(kept, lines[end:]...)
+
+	return strings.Join(kept, "\n"), when
+}
+
+// matchConstraint evaluates a "when:" expression against tags. Terms are
+// comma-separated and ANDed together; a "!" prefix negates a term. A tag not
+// present in the map is treated as false, matching Go's build-constraint
+// behavior for unknown tags.
+func matchConstraint(expr string, tags map[string]bool) bool {
+	for _, term := range strings.Split(expr, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		negate := strings.HasPrefix(term, "!")
+		if negate {
+			term = strings.TrimPrefix(term, "!")
+		}
+
+		value := tags[term]
+		if negate {
+			value = !value
+		}
+		if !value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DetectTags auto-detects facts about the project rooted at dir, for use as
+// the active tag set when none are explicitly passed via --tag. It never
+// returns an error; an undetectable fact is simply absent (and so defaults
+// to false in matchConstraint).
+func DetectTags(dir string) map[string]bool {
+	tags := map[string]bool{
+		runtime.GOOS:   true,
+		runtime.GOARCH: true,
+	}
+
+	if dirExists(dir, ".git") {
+		tags["has_git"] = true
+	}
+	if fileExists(dir, "Dockerfile") {
+		tags["has_dockerfile"] = true
+		tags["docker"] = true
+		tags["language:docker"] = true
+	}
+	if fileExists(dir, "go.mod") {
+		tags["go"] = true
+		tags["language:go"] = true
+	}
+
+	return tags
+}
+
+func dirExists(dir, name string) bool {
+	info, err := os.Stat(filepath.Join(dir, name))
+	return err == nil && info.IsDir()
+}
+
+func fileExists(dir, name string) bool {
+	info, err := os.Stat(filepath.Join(dir, name))
+	return err == nil && !info.IsDir()
+}
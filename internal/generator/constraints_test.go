@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractWhenConstraint(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantBody string
+		wantWhen string
+	}{
+		{
+			name: "quoted when is extracted and stripped",
+			content: `---
+name: foo
+when: "go,linux,!windows"
+---
+
+body`,
+			wantBody: `---
+name: foo
+---
+
+body`,
+			wantWhen: "go,linux,!windows",
+		},
+		{
+			name: "no when line leaves content untouched",
+			content: `---
+name: foo
+---
+
+body`,
+			wantBody: `---
+name: foo
+---
+
+body`,
+			wantWhen: "",
+		},
+		{
+			name:     "no front matter leaves content untouched",
+			content:  "just a plain template",
+			wantBody: "just a plain template",
+			wantWhen: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, when := extractWhenConstraint(tt.content)
+			assert.Equal(t, tt.wantBody, body)
+			assert.Equal(t, tt.wantWhen, when)
+		})
+	}
+}
+
+func TestMatchConstraint(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		tags map[string]bool
+		want bool
+	}{
+		{
+			name: "empty expression always matches",
+			expr: "",
+			tags: nil,
+			want: true,
+		},
+		{
+			name: "single positive tag present",
+			expr: "go",
+			tags: map[string]bool{"go": true},
+			want: true,
+		},
+		{
+			name: "single positive tag absent defaults to false",
+			expr: "go",
+			tags: nil,
+			want: false,
+		},
+		{
+			name: "comma is AND, all must hold",
+			expr: "go,linux",
+			tags: map[string]bool{"go": true, "linux": true},
+			want: true,
+		},
+		{
+			name: "comma is AND, one missing fails",
+			expr: "go,linux",
+			tags: map[string]bool{"go": true},
+			want: false,
+		},
+		{
+			name: "negated tag present is false",
+			expr: "!windows",
+			tags: map[string]bool{"windows": true},
+			want: false,
+		},
+		{
+			name: "negated tag absent is true",
+			expr: "!windows",
+			tags: map[string]bool{"linux": true},
+			want: true,
+		},
+		{
+			name: "mixed AND and NOT",
+			expr: "go,linux,!windows",
+			tags: map[string]bool{"go": true, "linux": true},
+			want: true,
+		},
+		{
+			name: "mixed AND and NOT, negated tag present fails",
+			expr: "go,linux,!windows",
+			tags: map[string]bool{"go": true, "linux": true, "windows": true},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchConstraint(tt.expr, tt.tags))
+		})
+	}
+}
+
+func TestEngine_Match(t *testing.T) {
+	userDir := t.TempDir()
+	writeTemplateFile(t, userDir, "commands/linux-only.md.tmpl", `---
+name: linux-only
+when: "linux,!windows"
+---
+content`)
+	writeTemplateFile(t, userDir, "commands/always.md.tmpl", "content")
+
+	engine, err := NewEngine(WithUserTemplatesDir(userDir))
+	require.NoError(t, err)
+
+	assert.True(t, engine.Match(ItemTypeCommand, "always", nil))
+	assert.False(t, engine.Match(ItemTypeCommand, "linux-only", nil))
+	assert.True(t, engine.Match(ItemTypeCommand, "linux-only", map[string]bool{"linux": true}))
+	assert.False(t, engine.Match(ItemTypeCommand, "linux-only", map[string]bool{"linux": true, "windows": true}))
+
+	// The "when:" line must not leak into the rendered output.
+	content, err := engine.Generate(ItemTypeCommand, "linux-only")
+	require.NoError(t, err)
+	assert.NotContains(t, content, "when:")
+}
+
+func TestGenerator_GenerateAll_FiltersByTags(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("CLAUDE_TEMPLATES_DIR", userDir)
+	t.Setenv("CLAUDE_PROJECT_TEMPLATES_DIR", t.TempDir())
+	writeTemplateFile(t, userDir, "commands/linux-only.md.tmpl", `---
+when: "linux"
+---
+content`)
+
+	tempDir := t.TempDir()
+	gen, err := NewGenerator(&Config{OutputDir: tempDir})
+	require.NoError(t, err)
+
+	require.NoError(t, gen.GenerateAll(ItemTypeCommand, nil))
+	_, err = os.Stat(filepath.Join(tempDir, "commands", "linux-only.md"))
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, gen.GenerateAll(ItemTypeCommand, map[string]bool{"linux": true}))
+	_, err = os.Stat(filepath.Join(tempDir, "commands", "linux-only.md"))
+	require.NoError(t, err)
+}
+
+func TestGenerator_List_AnnotatesMatches(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("CLAUDE_TEMPLATES_DIR", userDir)
+	t.Setenv("CLAUDE_PROJECT_TEMPLATES_DIR", t.TempDir())
+	writeTemplateFile(t, userDir, "commands/linux-only.md.tmpl", `---
+when: "linux"
+---
+content`)
+
+	gen, err := NewGenerator(&Config{OutputDir: t.TempDir()})
+	require.NoError(t, err)
+
+	infos := gen.List(ItemTypeCommand, nil)
+	var found bool
+	for _, info := range infos {
+		if info.Name == "linux-only" {
+			found = true
+			assert.False(t, info.Matches)
+		}
+	}
+	assert.True(t, found)
+
+	infos = gen.List(ItemTypeCommand, map[string]bool{"linux": true})
+	for _, info := range infos {
+		if info.Name == "linux-only" {
+			assert.True(t, info.Matches)
+		}
+	}
+}
+
+func TestDetectTags(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example"), 0644))
+
+	tags := DetectTags(dir)
+
+	assert.True(t, tags["has_git"])
+	assert.True(t, tags["go"])
+	assert.True(t, tags["language:go"])
+	assert.False(t, tags["has_dockerfile"])
+}
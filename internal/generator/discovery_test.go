@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTemplateFile(t *testing.T, root, relativePath, content string) {
+	t.Helper()
+	fullPath := filepath.Join(root, relativePath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+	require.NoError(t, os.WriteFile(fullPath, []byte(content), 0644))
+}
+
+func TestNewEngine_UserAndProjectTemplateRoots(t *testing.T) {
+	userDir := t.TempDir()
+	projectDir := t.TempDir()
+
+	writeTemplateFile(t, userDir, "skills/custom/SKILL.md.tmpl", "custom skill from user root")
+	writeTemplateFile(t, userDir, "agents/custom-agent.md.tmpl", "custom agent from user root")
+
+	engine, err := NewEngine(
+		WithUserTemplatesDir(userDir),
+		WithProjectTemplatesDir(projectDir),
+	)
+	require.NoError(t, err)
+
+	assert.Contains(t, engine.List(ItemTypeSkill), "custom")
+	assert.Contains(t, engine.List(ItemTypeAgent), "custom-agent")
+
+	content, err := engine.Generate(ItemTypeSkill, "custom")
+	require.NoError(t, err)
+	assert.Equal(t, "custom skill from user root", content)
+
+	infos := engine.ListInfo(ItemTypeSkill, nil)
+	var found bool
+	for _, info := range infos {
+		if info.Name == "custom" {
+			found = true
+			assert.Equal(t, TemplateSourceUser, info.Source)
+		}
+	}
+	assert.True(t, found, "expected custom skill to be listed")
+}
+
+func TestNewEngine_ProjectShadowsUserShadowsEmbedded(t *testing.T) {
+	userDir := t.TempDir()
+	projectDir := t.TempDir()
+
+	writeTemplateFile(t, userDir, "skills/coding/SKILL.md.tmpl", "user coding override")
+	writeTemplateFile(t, projectDir, "skills/coding/SKILL.md.tmpl", "project coding override")
+
+	engine, err := NewEngine(
+		WithUserTemplatesDir(userDir),
+		WithProjectTemplatesDir(projectDir),
+	)
+	require.NoError(t, err)
+
+	content, err := engine.Generate(ItemTypeSkill, "coding")
+	require.NoError(t, err)
+	assert.Equal(t, "project coding override", content)
+
+	infos := engine.ListInfo(ItemTypeSkill, nil)
+	for _, info := range infos {
+		if info.Name == "coding" {
+			assert.Equal(t, TemplateSourceProject, info.Source)
+		}
+	}
+}
+
+func TestNewEngine_MissingTemplateRootsAreIgnored(t *testing.T) {
+	engine, err := NewEngine(
+		WithUserTemplatesDir(filepath.Join(t.TempDir(), "does-not-exist")),
+		WithProjectTemplatesDir(filepath.Join(t.TempDir(), "does-not-exist")),
+	)
+	require.NoError(t, err)
+
+	assert.Contains(t, engine.List(ItemTypeSkill), "coding")
+}
+
+func TestConfig_resolvedUserTemplatesDir(t *testing.T) {
+	t.Run("configured value wins", func(t *testing.T) {
+		cfg := &Config{UserTemplatesDir: "/configured/templates"}
+		dir, err := cfg.resolvedUserTemplatesDir()
+		require.NoError(t, err)
+		assert.Equal(t, "/configured/templates", dir)
+	})
+
+	t.Run("env var wins over default", func(t *testing.T) {
+		t.Setenv(envUserTemplatesDir, "/from/env")
+		cfg := &Config{}
+		dir, err := cfg.resolvedUserTemplatesDir()
+		require.NoError(t, err)
+		assert.Equal(t, "/from/env", dir)
+	})
+
+	t.Run("falls back to default", func(t *testing.T) {
+		cfg := &Config{}
+		dir, err := cfg.resolvedUserTemplatesDir()
+		require.NoError(t, err)
+		homeDir, err := os.UserHomeDir()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(homeDir, ".claude", "templates"), dir)
+	})
+}
+
+func TestConfig_resolvedProjectTemplatesDir(t *testing.T) {
+	t.Run("env var wins over default", func(t *testing.T) {
+		t.Setenv(envProjectTemplatesDir, "/from/project/env")
+		cfg := &Config{}
+		dir, err := cfg.resolvedProjectTemplatesDir()
+		require.NoError(t, err)
+		assert.Equal(t, "/from/project/env", dir)
+	})
+}
@@ -1,7 +1,10 @@
 package generator
 
 import (
+	"errors"
 	"fmt"
+
+	"github.com/spf13/afero"
 )
 
 // Generator orchestrates template generation and file writing
@@ -13,12 +16,33 @@ type Generator struct {
 
 // NewGenerator creates a new Generator with the given config
 func NewGenerator(config *Config) (*Generator, error) {
-	engine, err := NewEngine()
+	userTemplatesDir, err := config.resolvedUserTemplatesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve user templates dir: %w", err)
+	}
+
+	projectTemplatesDir, err := config.resolvedProjectTemplatesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project templates dir: %w", err)
+	}
+
+	engine, err := NewEngine(
+		WithUserTemplatesDir(userTemplatesDir),
+		WithProjectTemplatesDir(projectTemplatesDir),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create engine: %w", err)
 	}
 
-	writer := NewWriter(config)
+	fs := afero.Fs(afero.NewOsFs())
+	if config.ChrootDir != "" {
+		chrootDir, err := expandHomeDir(config.ChrootDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand chroot dir: %w", err)
+		}
+		fs = afero.NewBasePathFs(fs, chrootDir)
+	}
+	writer := NewWriterWithFs(config, fs)
 
 	return &Generator{
 		config: config,
@@ -29,7 +53,14 @@ func NewGenerator(config *Config) (*Generator, error) {
 
 // Generate generates content for a specific item and writes it to a file
 func (g *Generator) Generate(itemType ItemType, name string) error {
-	content, err := g.engine.Generate(itemType, name)
+	return g.GenerateWithParams(itemType, name, nil)
+}
+
+// GenerateWithParams generates content for a specific item using the given
+// parameter values and writes it to a file. Values are validated against the
+// template's schema.yaml, if it has one.
+func (g *Generator) GenerateWithParams(itemType ItemType, name string, values map[string]any) error {
+	content, err := g.engine.GenerateWithParams(itemType, name, values)
 	if err != nil {
 		return fmt.Errorf("failed to generate content: %w", err)
 	}
@@ -41,20 +72,66 @@ func (g *Generator) Generate(itemType ItemType, name string) error {
 	return nil
 }
 
-// GenerateAll generates all templates of the given type
-func (g *Generator) GenerateAll(itemType ItemType) error {
-	templates := g.engine.List(itemType)
+// Schema returns the declared parameters for the named template, or nil if
+// it has no schema.yaml.
+func (g *Generator) Schema(itemType ItemType, name string) *Schema {
+	return g.engine.Schema(itemType, name)
+}
+
+// GenerateAll generates every template of the given type whose "when:"
+// constraint matches tags (all of them, if tags is nil and none declare a
+// constraint). A conflict that resolves to Skip is not an error; other
+// per-file failures are collected and reported together once every matching
+// template has been attempted, rather than aborting on the first one. An
+// interactive abort (ErrAborted) stops the run immediately, since it
+// reflects the user asking to stop.
+func (g *Generator) GenerateAll(itemType ItemType, tags map[string]bool) error {
+	var names []string
+	for _, name := range g.engine.List(itemType) {
+		if g.engine.Match(itemType, name, tags) {
+			names = append(names, name)
+		}
+	}
 
-	for _, name := range templates {
-		if err := g.Generate(itemType, name); err != nil {
+	var failures []error
+	for _, name := range names {
+		err := g.Generate(itemType, name)
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, ErrAborted) {
 			return err
 		}
+		failures = append(failures, fmt.Errorf("%s: %w", name, err))
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to generate %d of %d %s: %w", len(failures), len(names), itemType, errors.Join(failures...))
 	}
 
 	return nil
 }
 
-// List returns available template names for the given item type
-func (g *Generator) List(itemType ItemType) []string {
-	return g.engine.List(itemType)
+// List returns available templates for the given item type, annotated with
+// the root each one was discovered in and whether it matches tags.
+func (g *Generator) List(itemType ItemType, tags map[string]bool) []TemplateInfo {
+	return g.engine.ListInfo(itemType, tags)
+}
+
+// NewTemplate scaffolds a new custom template named name under the
+// project template root if project is true, or the user template root
+// otherwise. See (*Generator).writeScaffold for the on-disk layout.
+func (g *Generator) NewTemplate(itemType ItemType, name string, project bool, from string, force bool) error {
+	var targetDir string
+	var err error
+	if project {
+		targetDir, err = g.config.resolvedProjectTemplatesDir()
+	} else {
+		targetDir, err = g.config.resolvedUserTemplatesDir()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve template root: %w", err)
+	}
+
+	return g.newTemplate(itemType, name, targetDir, from, force)
 }
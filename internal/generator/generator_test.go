@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -9,7 +10,19 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// isolateTemplateDirs points the user/project template roots at empty temp
+// directories for the duration of the test, so a test that builds a bare
+// Config doesn't pick up the real ~/.claude/templates or ./.claude/templates
+// on whatever machine happens to run it.
+func isolateTemplateDirs(t *testing.T) {
+	t.Helper()
+	t.Setenv(envUserTemplatesDir, t.TempDir())
+	t.Setenv(envProjectTemplatesDir, t.TempDir())
+}
+
 func TestNewGenerator(t *testing.T) {
+	isolateTemplateDirs(t)
+
 	tests := []struct {
 		name    string
 		config  *Config
@@ -52,6 +65,8 @@ func TestNewGenerator(t *testing.T) {
 }
 
 func TestGenerator_Generate_Success(t *testing.T) {
+	isolateTemplateDirs(t)
+
 	tests := []struct {
 		name         string
 		itemType     ItemType
@@ -105,6 +120,8 @@ func TestGenerator_Generate_Success(t *testing.T) {
 }
 
 func TestGenerator_Generate_Errors(t *testing.T) {
+	isolateTemplateDirs(t)
+
 	tests := []struct {
 		name         string
 		itemType     ItemType
@@ -145,6 +162,8 @@ func TestGenerator_Generate_Errors(t *testing.T) {
 }
 
 func TestGenerator_GenerateAll_Success(t *testing.T) {
+	isolateTemplateDirs(t)
+
 	tests := []struct {
 		name     string
 		itemType ItemType
@@ -178,7 +197,7 @@ func TestGenerator_GenerateAll_Success(t *testing.T) {
 			gen, err := NewGenerator(config)
 			require.NoError(t, err)
 
-			err = gen.GenerateAll(tt.itemType)
+			err = gen.GenerateAll(tt.itemType, nil)
 
 			require.NoError(t, err)
 
@@ -202,6 +221,8 @@ func TestGenerator_GenerateAll_Success(t *testing.T) {
 }
 
 func TestGenerator_GenerateAll_Errors(t *testing.T) {
+	isolateTemplateDirs(t)
+
 	tests := []struct {
 		name       string
 		itemType   ItemType
@@ -239,7 +260,7 @@ func TestGenerator_GenerateAll_Errors(t *testing.T) {
 			gen, err := NewGenerator(config)
 			require.NoError(t, err)
 
-			err = gen.GenerateAll(tt.itemType)
+			err = gen.GenerateAll(tt.itemType, nil)
 
 			require.Error(t, err)
 			assert.Contains(t, err.Error(), tt.wantErrMsg)
@@ -247,11 +268,81 @@ func TestGenerator_GenerateAll_Errors(t *testing.T) {
 	}
 }
 
+func TestGenerator_GenerateAll_SkipPolicyLeavesExistingFilesAlone(t *testing.T) {
+	isolateTemplateDirs(t)
+
+	tempDir := t.TempDir()
+	config := &Config{OutputDir: tempDir}
+
+	gen, err := NewGenerator(config)
+	require.NoError(t, err)
+	require.NoError(t, gen.GenerateAll(ItemTypeCommand, nil))
+
+	outputPath, err := gen.writer.GetOutputPath(ItemTypeCommand, "feature")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(outputPath, []byte("hand-edited"), 0644))
+
+	gen, err = NewGenerator(&Config{OutputDir: tempDir, ConflictPolicy: ConflictPolicySkip})
+	require.NoError(t, err)
+	require.NoError(t, gen.GenerateAll(ItemTypeCommand, nil))
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, "hand-edited", string(content))
+}
+
+func TestGenerator_GenerateAll_FailPolicyAggregatesAllFailures(t *testing.T) {
+	isolateTemplateDirs(t)
+
+	tempDir := t.TempDir()
+	config := &Config{OutputDir: tempDir}
+
+	gen, err := NewGenerator(config)
+	require.NoError(t, err)
+	require.NoError(t, gen.GenerateAll(ItemTypeCommand, nil))
+
+	gen, err = NewGenerator(&Config{OutputDir: tempDir, ConflictPolicy: ConflictPolicyFail})
+	require.NoError(t, err)
+
+	err = gen.GenerateAll(ItemTypeCommand, nil)
+	require.Error(t, err)
+
+	names := gen.engine.List(ItemTypeCommand)
+	assert.Contains(t, err.Error(), fmt.Sprintf("failed to generate %d of %d", len(names), len(names)))
+	for _, name := range names {
+		assert.Contains(t, err.Error(), name)
+	}
+}
+
+func TestGenerator_Generate_Chroot(t *testing.T) {
+	isolateTemplateDirs(t)
+
+	chrootDir := t.TempDir()
+
+	config := &Config{
+		OutputDir: "/output",
+		ChrootDir: chrootDir,
+	}
+
+	gen, err := NewGenerator(config)
+	require.NoError(t, err)
+
+	err = gen.Generate(ItemTypeSkill, "coding")
+	require.NoError(t, err)
+
+	// The file should land inside chrootDir, not at the literal /output path.
+	_, err = os.Stat(filepath.Join(chrootDir, "output", "skills", "coding", "SKILL.md"))
+	require.NoError(t, err)
+}
+
 func TestGenerator_List(t *testing.T) {
+	isolateTemplateDirs(t)
+
 	tests := []struct {
 		name         string
 		itemType     ItemType
 		wantContains []string
+		wantEmpty    bool
 	}{
 		{
 			name:         "list skills",
@@ -269,9 +360,9 @@ func TestGenerator_List(t *testing.T) {
 			wantContains: []string{"feature", "fix", "refactor"},
 		},
 		{
-			name:         "list invalid type returns empty",
-			itemType:     ItemType("invalid"),
-			wantContains: []string{},
+			name:      "list invalid type returns empty",
+			itemType:  ItemType("invalid"),
+			wantEmpty: true,
 		},
 	}
 
@@ -285,16 +376,30 @@ func TestGenerator_List(t *testing.T) {
 			gen, err := NewGenerator(config)
 			require.NoError(t, err)
 
-			result := gen.List(tt.itemType)
+			result := gen.List(tt.itemType, nil)
+
+			if tt.wantEmpty {
+				assert.Empty(t, result)
+				return
+			}
+
+			var names []string
+			for _, info := range result {
+				names = append(names, info.Name)
+				assert.Equal(t, TemplateSourceEmbedded, info.Source)
+				assert.True(t, info.Matches)
+			}
 
 			for _, want := range tt.wantContains {
-				assert.Contains(t, result, want)
+				assert.Contains(t, names, want)
 			}
 		})
 	}
 }
 
 func TestGenerator_Generate_DryRun(t *testing.T) {
+	isolateTemplateDirs(t)
+
 	tests := []struct {
 		name         string
 		itemType     ItemType
@@ -0,0 +1,255 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parameter declares a single typed template parameter, normally loaded
+// from a template's schema.yaml.
+type Parameter struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"` // string, bool, int, enum
+	Default     any      `yaml:"default"`
+	Description string   `yaml:"description"`
+	Enum        []string `yaml:"enum"`
+	Required    bool     `yaml:"required"`
+	Validate    string   `yaml:"validate"` // regex the value must match
+}
+
+// Schema declares the parameters a template accepts.
+type Schema struct {
+	Parameters []Parameter `yaml:"parameters"`
+}
+
+// schemaFileName is the name of the optional schema file in a skill's
+// template directory. Agent and command templates use
+// "<name>.schema.yaml" next to their "<name>.md.tmpl" instead, since they
+// aren't given their own directory.
+const schemaFileName = "schema.yaml"
+
+// parseSchema parses schema.yaml content.
+func parseSchema(content []byte) (*Schema, error) {
+	var schema Schema
+	if err := yaml.Unmarshal(content, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// validateParams merges values onto the schema's defaults, then validates
+// required, enum, and regex constraints. A nil schema means the template
+// takes no declared parameters; values are passed through unvalidated.
+func validateParams(schema *Schema, values map[string]any) (map[string]any, error) {
+	result := make(map[string]any, len(values))
+	for k, v := range values {
+		result[k] = v
+	}
+
+	if schema == nil {
+		return result, nil
+	}
+
+	for _, p := range schema.Parameters {
+		v, provided := result[p.Name]
+		if provided {
+			if s, ok := v.(string); ok && s == "" {
+				provided = false
+			}
+		}
+
+		if !provided && p.Default != nil {
+			v = p.Default
+			result[p.Name] = v
+			provided = true
+		}
+
+		if !provided {
+			if p.Required {
+				return nil, fmt.Errorf("missing required parameter %q", p.Name)
+			}
+			continue
+		}
+
+		v, err := coerceParamType(p, v)
+		if err != nil {
+			return nil, err
+		}
+		result[p.Name] = v
+
+		str := fmt.Sprintf("%v", v)
+
+		if len(p.Enum) > 0 && !slices.Contains(p.Enum, str) {
+			return nil, fmt.Errorf("parameter %q must be one of %v, got %q", p.Name, p.Enum, str)
+		}
+
+		if p.Validate != "" {
+			re, err := regexp.Compile(p.Validate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid validate pattern for parameter %q: %w", p.Name, err)
+			}
+			if !re.MatchString(str) {
+				return nil, fmt.Errorf("parameter %q value %q does not match pattern %q", p.Name, str, p.Validate)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// coerceParamType converts v to the Go type p.Type declares, so a parameter
+// has the same type in .Params regardless of whether it came from a
+// schema.yaml default (already typed by YAML) or --set/--params-file (always
+// a string). An empty Type is untyped and passed through as-is.
+func coerceParamType(p Parameter, v any) (any, error) {
+	switch p.Type {
+	case "":
+		return v, nil
+
+	case "string", "enum":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return fmt.Sprintf("%v", v), nil
+
+	case "bool":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("parameter %q must be a bool, got %v", p.Name, v)
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q must be a bool, got %q", p.Name, s)
+		}
+		return b, nil
+
+	case "int":
+		switch n := v.(type) {
+		case int:
+			return n, nil
+		case string:
+			i, err := strconv.Atoi(n)
+			if err != nil {
+				return nil, fmt.Errorf("parameter %q must be an int, got %q", p.Name, n)
+			}
+			return i, nil
+		default:
+			return nil, fmt.Errorf("parameter %q must be an int, got %v", p.Name, v)
+		}
+
+	default:
+		return nil, fmt.Errorf("parameter %q has unknown type %q", p.Name, p.Type)
+	}
+}
+
+// LoadParamsFile reads a YAML file of parameter values for non-interactive
+// generation, e.g. in CI via --params-file.
+func LoadParamsFile(path string) (map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read params file %s: %w", path, err)
+	}
+
+	var values map[string]any
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse params file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// templateFuncMap returns the helper functions exposed to every template in
+// addition to the standard text/template builtins.
+func templateFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"snake_case":  snakeCase,
+		"pascal_case": pascalCase,
+		"now": func() string {
+			return time.Now().Format(time.RFC3339)
+		},
+		"default": func(def, val any) any {
+			if val == nil {
+				return def
+			}
+			if s, ok := val.(string); ok && s == "" {
+				return def
+			}
+			return val
+		},
+		"env": os.Getenv,
+		"contains": func(substr, s string) bool {
+			return strings.Contains(s, substr)
+		},
+	}
+}
+
+// wordBoundary reports whether r should split an identifier into words,
+// i.e. it is neither a letter nor a digit.
+func wordBoundary(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+}
+
+// splitWords breaks s into words on non-alphanumeric separators and on
+// lower-to-upper case transitions, e.g. "myHTTPServer" -> ["my", "HTTP", "Server"].
+func splitWords(s string) []string {
+	var words []string
+	var current strings.Builder
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if wordBoundary(r) {
+			if current.Len() > 0 {
+				words = append(words, current.String())
+				current.Reset()
+			}
+			continue
+		}
+
+		if i > 0 && current.Len() > 0 && r >= 'A' && r <= 'Z' {
+			prev := runes[i-1]
+			lowerToUpper := prev >= 'a' && prev <= 'z'
+			// End of an acronym run, e.g. the "S" in "HTTPServer".
+			acronymBoundary := prev >= 'A' && prev <= 'Z' && i+1 < len(runes) && runes[i+1] >= 'a' && runes[i+1] <= 'z'
+			if lowerToUpper || acronymBoundary {
+				words = append(words, current.String())
+				current.Reset()
+			}
+		}
+
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		words = append(words, current.String())
+	}
+
+	return words
+}
+
+// snakeCase converts s to snake_case, e.g. "MyFeature" -> "my_feature".
+func snakeCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return strings.Join(words, "_")
+}
+
+// pascalCase converts s to PascalCase, e.g. "my_feature" -> "MyFeature".
+func pascalCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+	}
+	return strings.Join(words, "")
+}
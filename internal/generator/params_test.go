@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateParams(t *testing.T) {
+	schema := &Schema{
+		Parameters: []Parameter{
+			{Name: "language", Default: "go"},
+			{Name: "framework", Required: true},
+			{Name: "level", Enum: []string{"low", "medium", "high"}},
+			{Name: "name", Validate: `^[a-z][a-z0-9-]*$`},
+		},
+	}
+
+	t.Run("applies defaults", func(t *testing.T) {
+		result, err := validateParams(schema, map[string]any{"framework": "cobra"})
+		require.NoError(t, err)
+		assert.Equal(t, "go", result["language"])
+	})
+
+	t.Run("missing required parameter errors", func(t *testing.T) {
+		_, err := validateParams(schema, map[string]any{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `missing required parameter "framework"`)
+	})
+
+	t.Run("enum violation errors", func(t *testing.T) {
+		_, err := validateParams(schema, map[string]any{"framework": "cobra", "level": "extreme"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `parameter "level" must be one of`)
+	})
+
+	t.Run("regex violation errors", func(t *testing.T) {
+		_, err := validateParams(schema, map[string]any{"framework": "cobra", "name": "Bad Name"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `does not match pattern`)
+	})
+
+	t.Run("nil schema passes values through", func(t *testing.T) {
+		result, err := validateParams(nil, map[string]any{"anything": "goes"})
+		require.NoError(t, err)
+		assert.Equal(t, "goes", result["anything"])
+	})
+}
+
+func TestValidateParams_TypeCoercion(t *testing.T) {
+	schema := &Schema{
+		Parameters: []Parameter{
+			{Name: "count", Type: "int"},
+			{Name: "enabled", Type: "bool"},
+			{Name: "label", Type: "string"},
+		},
+	}
+
+	t.Run("coerces string flag values to their declared type", func(t *testing.T) {
+		result, err := validateParams(schema, map[string]any{"count": "3", "enabled": "false", "label": "ok"})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result["count"])
+		assert.Equal(t, false, result["enabled"])
+		assert.Equal(t, "ok", result["label"])
+	})
+
+	t.Run("leaves already-typed default values alone", func(t *testing.T) {
+		result, err := validateParams(schema, map[string]any{"count": 3, "enabled": true, "label": "ok"})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result["count"])
+		assert.Equal(t, true, result["enabled"])
+	})
+
+	t.Run("invalid int errors", func(t *testing.T) {
+		_, err := validateParams(schema, map[string]any{"count": "not-a-number", "enabled": "true", "label": "ok"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `parameter "count" must be an int`)
+	})
+
+	t.Run("invalid bool errors", func(t *testing.T) {
+		_, err := validateParams(schema, map[string]any{"count": "3", "enabled": "maybe", "label": "ok"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `parameter "enabled" must be a bool`)
+	})
+}
+
+func TestLoadParamsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "params.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("language: rust\nframework: actix\n"), 0644))
+
+	values, err := LoadParamsFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "rust", values["language"])
+	assert.Equal(t, "actix", values["framework"])
+}
+
+func TestEngine_GenerateWithParams(t *testing.T) {
+	engine, err := NewEngine()
+	require.NoError(t, err)
+
+	t.Run("uses schema default when not set", func(t *testing.T) {
+		content, err := engine.GenerateWithParams(ItemTypeSkill, "coding", nil)
+		require.NoError(t, err)
+		assert.Contains(t, content, "Write go code")
+	})
+
+	t.Run("uses provided value over default", func(t *testing.T) {
+		content, err := engine.GenerateWithParams(ItemTypeSkill, "coding", map[string]any{"language": "rust", "framework": "actix"})
+		require.NoError(t, err)
+		assert.Contains(t, content, "Write rust code")
+		assert.Contains(t, content, "This project uses actix")
+	})
+}
+
+func TestSnakeCaseAndPascalCase(t *testing.T) {
+	assert.Equal(t, "my_feature", snakeCase("MyFeature"))
+	assert.Equal(t, "my_http_server", snakeCase("myHTTPServer"))
+	assert.Equal(t, "MyFeature", pascalCase("my_feature"))
+	assert.Equal(t, "MyHttpServer", pascalCase("my-http-server"))
+}
@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// scaffoldTemplatePath and scaffoldSchemaPath return the on-disk paths a new
+// custom template's files should be written to under a template root, e.g.
+// "skills/foo/SKILL.md.tmpl" and "skills/foo/schema.yaml", or
+// "agents/foo.md.tmpl" and "agents/foo.schema.yaml".
+func scaffoldTemplatePath(itemType ItemType, name string) (string, error) {
+	switch itemType {
+	case ItemTypeSkill:
+		return filepath.Join("skills", name, "SKILL.md.tmpl"), nil
+	case ItemTypeAgent:
+		return filepath.Join("agents", name+".md.tmpl"), nil
+	case ItemTypeCommand:
+		return filepath.Join("commands", name+".md.tmpl"), nil
+	default:
+		return "", fmt.Errorf("unknown item type: %s", itemType)
+	}
+}
+
+func scaffoldSchemaPath(itemType ItemType, name string) (string, error) {
+	if itemType == ItemTypeSkill {
+		return filepath.Join("skills", name, schemaFileName), nil
+	}
+	templatePath, err := scaffoldTemplatePath(itemType, name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(templatePath), name+".schema.yaml"), nil
+}
+
+// starterTemplate returns the seed content for a brand new template: a
+// minimal, working template that echoes its own parameters, plus a matching
+// schema.yaml declaring them.
+func starterTemplate(itemType ItemType, name string) (templateContent, schemaContent string) {
+	schemaContent = fmt.Sprintf(`parameters:
+  - name: name
+    type: string
+    default: %s
+    description: Display name
+  - name: description
+    type: string
+    default: "TODO: describe what this %s does"
+    description: One-line description
+`, name, itemType)
+
+	switch itemType {
+	case ItemTypeSkill:
+		templateContent = `---
+name: {{.Params.name}}
+description: {{.Params.description}}
+---
+
+# {{.Params.name}}
+
+{{.Params.description}}
+`
+	case ItemTypeAgent:
+		templateContent = `---
+name: {{.Params.name}}
+description: {{.Params.description}}
+---
+
+You are {{.Params.name}}. {{.Params.description}}
+`
+	case ItemTypeCommand:
+		templateContent = `---
+name: {{.Params.name}}
+description: {{.Params.description}}
+---
+
+# {{.Params.name}}
+
+{{.Params.description}}
+`
+	}
+
+	return templateContent, schemaContent
+}
+
+// newTemplate scaffolds a new custom template of the given item type under
+// targetDir, seeding it with either a minimal starter template or, if from
+// is non-empty, a copy of that already-installed template (embedded, user,
+// or project). It refuses to overwrite an existing template unless force is
+// true.
+func (g *Generator) newTemplate(itemType ItemType, name string, targetDir string, from string, force bool) error {
+	templatePath, err := scaffoldTemplatePath(itemType, name)
+	if err != nil {
+		return err
+	}
+	schemaPath, err := scaffoldSchemaPath(itemType, name)
+	if err != nil {
+		return err
+	}
+
+	absTemplatePath := filepath.Join(targetDir, templatePath)
+	if !force {
+		if _, err := g.writer.fs.Stat(absTemplatePath); err == nil {
+			return fmt.Errorf("template %s %q already exists at %s (use --force to overwrite)", itemType, name, absTemplatePath)
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to check existing template %s: %w", absTemplatePath, err)
+		}
+	}
+
+	templateContent, schemaContent := starterTemplate(itemType, name)
+	if from != "" {
+		forkedTemplate, forkedSchema, ok := g.engine.RawTemplate(itemType, from)
+		if !ok {
+			return fmt.Errorf("template %s %q not found to fork from", itemType, from)
+		}
+		templateContent, schemaContent = forkedTemplate, forkedSchema
+	}
+
+	return g.writeScaffold(targetDir, templatePath, schemaPath, templateContent, schemaContent)
+}
+
+// writeScaffold writes a new template's files through the Generator's
+// configured afero.Fs (the same one Writer uses, so --chroot and
+// afero.NewMemMapFs()-backed tests apply here too), creating parent
+// directories as needed. An empty schemaContent is written as-is if
+// non-empty, and skipped entirely if empty (a forked template with no
+// schema.yaml should not gain one).
+func (g *Generator) writeScaffold(targetDir, templatePath, schemaPath, templateContent, schemaContent string) error {
+	fs := g.writer.fs
+
+	absTemplatePath := filepath.Join(targetDir, templatePath)
+	if err := fs.MkdirAll(filepath.Dir(absTemplatePath), 0755); err != nil {
+		return fmt.Errorf("failed to create template directory %s: %w", filepath.Dir(absTemplatePath), err)
+	}
+	if err := afero.WriteFile(fs, absTemplatePath, []byte(templateContent), 0644); err != nil {
+		return fmt.Errorf("failed to write template %s: %w", absTemplatePath, err)
+	}
+
+	if schemaContent == "" {
+		return nil
+	}
+
+	absSchemaPath := filepath.Join(targetDir, schemaPath)
+	if err := fs.MkdirAll(filepath.Dir(absSchemaPath), 0755); err != nil {
+		return fmt.Errorf("failed to create schema directory %s: %w", filepath.Dir(absSchemaPath), err)
+	}
+	if err := afero.WriteFile(fs, absSchemaPath, []byte(schemaContent), 0644); err != nil {
+		return fmt.Errorf("failed to write schema %s: %w", absSchemaPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_NewTemplate_Starter(t *testing.T) {
+	tests := []struct {
+		name             string
+		itemType         ItemType
+		templateName     string
+		wantTemplatePath string
+		wantSchemaPath   string
+	}{
+		{
+			name:             "skill",
+			itemType:         ItemTypeSkill,
+			templateName:     "my-skill",
+			wantTemplatePath: "skills/my-skill/SKILL.md.tmpl",
+			wantSchemaPath:   "skills/my-skill/schema.yaml",
+		},
+		{
+			name:             "agent",
+			itemType:         ItemTypeAgent,
+			templateName:     "my-agent",
+			wantTemplatePath: "agents/my-agent.md.tmpl",
+			wantSchemaPath:   "agents/my-agent.schema.yaml",
+		},
+		{
+			name:             "command",
+			itemType:         ItemTypeCommand,
+			templateName:     "my-command",
+			wantTemplatePath: "commands/my-command.md.tmpl",
+			wantSchemaPath:   "commands/my-command.schema.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userDir := t.TempDir()
+			t.Setenv("CLAUDE_TEMPLATES_DIR", userDir)
+			projectDir := t.TempDir()
+			t.Setenv("CLAUDE_PROJECT_TEMPLATES_DIR", projectDir)
+
+			gen, err := NewGenerator(&Config{OutputDir: t.TempDir()})
+			require.NoError(t, err)
+
+			require.NoError(t, gen.NewTemplate(tt.itemType, tt.templateName, false, "", false))
+
+			templateContent, err := os.ReadFile(filepath.Join(userDir, tt.wantTemplatePath))
+			require.NoError(t, err)
+			assert.Contains(t, string(templateContent), ".Params.name")
+
+			schemaContent, err := os.ReadFile(filepath.Join(userDir, tt.wantSchemaPath))
+			require.NoError(t, err)
+			assert.Contains(t, string(schemaContent), "name: name")
+
+			// The new template is now discoverable and renders successfully.
+			gen, err = NewGenerator(&Config{OutputDir: t.TempDir()})
+			require.NoError(t, err)
+			assert.Contains(t, gen.List(tt.itemType, nil), TemplateInfo{Name: tt.templateName, Source: TemplateSourceUser, Matches: true})
+
+			_, err = gen.engine.Generate(tt.itemType, tt.templateName)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGenerator_NewTemplate_RefusesToOverwriteWithoutForce(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("CLAUDE_TEMPLATES_DIR", userDir)
+	t.Setenv("CLAUDE_PROJECT_TEMPLATES_DIR", t.TempDir())
+
+	gen, err := NewGenerator(&Config{OutputDir: t.TempDir()})
+	require.NoError(t, err)
+
+	require.NoError(t, gen.NewTemplate(ItemTypeSkill, "my-skill", false, "", false))
+
+	err = gen.NewTemplate(ItemTypeSkill, "my-skill", false, "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	require.NoError(t, gen.NewTemplate(ItemTypeSkill, "my-skill", false, "", true))
+}
+
+func TestGenerator_NewTemplate_Project(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("CLAUDE_TEMPLATES_DIR", userDir)
+	projectDir := t.TempDir()
+	t.Setenv("CLAUDE_PROJECT_TEMPLATES_DIR", projectDir)
+
+	gen, err := NewGenerator(&Config{OutputDir: t.TempDir()})
+	require.NoError(t, err)
+
+	require.NoError(t, gen.NewTemplate(ItemTypeSkill, "my-skill", true, "", false))
+
+	_, err = os.Stat(filepath.Join(projectDir, "skills/my-skill/SKILL.md.tmpl"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(userDir, "skills/my-skill/SKILL.md.tmpl"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerator_NewTemplate_From(t *testing.T) {
+	userDir := t.TempDir()
+	t.Setenv("CLAUDE_TEMPLATES_DIR", userDir)
+	t.Setenv("CLAUDE_PROJECT_TEMPLATES_DIR", t.TempDir())
+
+	gen, err := NewGenerator(&Config{OutputDir: t.TempDir()})
+	require.NoError(t, err)
+
+	require.NoError(t, gen.NewTemplate(ItemTypeSkill, "my-coding", false, "coding", false))
+
+	templateContent, err := os.ReadFile(filepath.Join(userDir, "skills/my-coding/SKILL.md.tmpl"))
+	require.NoError(t, err)
+	assert.Contains(t, string(templateContent), "Params.language")
+
+	schemaContent, err := os.ReadFile(filepath.Join(userDir, "skills/my-coding/schema.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(schemaContent), "name: language")
+}
+
+func TestGenerator_NewTemplate_Chroot(t *testing.T) {
+	chrootDir := t.TempDir()
+	t.Setenv("CLAUDE_TEMPLATES_DIR", "/templates")
+	t.Setenv("CLAUDE_PROJECT_TEMPLATES_DIR", t.TempDir())
+
+	gen, err := NewGenerator(&Config{OutputDir: t.TempDir(), ChrootDir: chrootDir})
+	require.NoError(t, err)
+
+	require.NoError(t, gen.NewTemplate(ItemTypeSkill, "my-skill", false, "", false))
+
+	// The template should land inside chrootDir, not at the literal /templates path.
+	_, err = os.Stat(filepath.Join(chrootDir, "templates", "skills/my-skill/SKILL.md.tmpl"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join("/templates", "skills/my-skill/SKILL.md.tmpl"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestGenerator_NewTemplate_FromUnknownTemplateErrors(t *testing.T) {
+	t.Setenv("CLAUDE_TEMPLATES_DIR", t.TempDir())
+	t.Setenv("CLAUDE_PROJECT_TEMPLATES_DIR", t.TempDir())
+
+	gen, err := NewGenerator(&Config{OutputDir: t.TempDir()})
+	require.NoError(t, err)
+
+	err = gen.NewTemplate(ItemTypeSkill, "my-skill", false, "nonexistent", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found to fork from")
+}
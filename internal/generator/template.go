@@ -0,0 +1,331 @@
+package generator
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var embeddedTemplatesFS embed.FS
+
+// ItemType identifies the kind of prompt a template produces.
+type ItemType string
+
+const (
+	ItemTypeSkill   ItemType = "skill"
+	ItemTypeAgent   ItemType = "agent"
+	ItemTypeCommand ItemType = "command"
+)
+
+// TemplateSource identifies where a template was discovered from.
+type TemplateSource string
+
+const (
+	// TemplateSourceEmbedded is a template baked into the binary.
+	TemplateSourceEmbedded TemplateSource = "embedded"
+	// TemplateSourceUser is a template from the per-user template root.
+	TemplateSourceUser TemplateSource = "user"
+	// TemplateSourceProject is a template from the per-project template root.
+	TemplateSourceProject TemplateSource = "project"
+)
+
+// TemplateInfo describes a discovered template, where it came from, and
+// whether it matches the tags it was listed with.
+type TemplateInfo struct {
+	Name    string
+	Source  TemplateSource
+	Matches bool
+}
+
+// templateGlobs maps each item type to the glob pattern used to discover its
+// templates within a template root (embedded or on disk).
+var templateGlobs = map[ItemType]string{
+	ItemTypeSkill:   "skills/*/SKILL.md.tmpl",
+	ItemTypeAgent:   "agents/*.md.tmpl",
+	ItemTypeCommand: "commands/*.md.tmpl",
+}
+
+// Engine loads and executes prompt templates for skills, agents, and commands.
+//
+// Templates are discovered from the embedded binary templates plus, when
+// configured, a per-user and a per-project template root. A template with
+// the same item type and name in a later root shadows an earlier one, with
+// precedence project > user > embedded.
+type Engine struct {
+	templates     map[ItemType]map[string]*template.Template
+	templateNames map[ItemType][]string
+	sources       map[ItemType]map[string]TemplateSource
+	schemas       map[ItemType]map[string]*Schema
+
+	// rawTemplates and rawSchemas retain the unparsed source of each
+	// template and its schema.yaml (if any), so `generator <kind> new
+	// --from` can fork an installed template byte-for-byte.
+	rawTemplates map[ItemType]map[string]string
+	rawSchemas   map[ItemType]map[string]string
+
+	// constraints holds each template's "when:" expression, if it declared
+	// one in its front matter. A template with no entry here always matches.
+	constraints map[ItemType]map[string]string
+}
+
+// templateContext is the data exposed to every template.
+type templateContext struct {
+	Params map[string]any
+}
+
+// EngineOption configures optional template roots for NewEngine.
+type EngineOption func(*engineOptions)
+
+type engineOptions struct {
+	userTemplatesDir    string
+	projectTemplatesDir string
+}
+
+// WithUserTemplatesDir registers a per-user template root. Templates found
+// here shadow embedded templates of the same item type and name.
+func WithUserTemplatesDir(dir string) EngineOption {
+	return func(o *engineOptions) { o.userTemplatesDir = dir }
+}
+
+// WithProjectTemplatesDir registers a per-project template root. Templates
+// found here shadow both user and embedded templates of the same item type
+// and name.
+func WithProjectTemplatesDir(dir string) EngineOption {
+	return func(o *engineOptions) { o.projectTemplatesDir = dir }
+}
+
+// NewEngine creates an Engine and loads the embedded templates plus any
+// additional roots configured via EngineOption.
+func NewEngine(opts ...EngineOption) (*Engine, error) {
+	var o engineOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	e := &Engine{
+		templates:     map[ItemType]map[string]*template.Template{},
+		templateNames: map[ItemType][]string{},
+		sources:       map[ItemType]map[string]TemplateSource{},
+		schemas:       map[ItemType]map[string]*Schema{},
+		rawTemplates:  map[ItemType]map[string]string{},
+		rawSchemas:    map[ItemType]map[string]string{},
+		constraints:   map[ItemType]map[string]string{},
+	}
+
+	embedded, err := fs.Sub(embeddedTemplatesFS, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded templates: %w", err)
+	}
+	if err := e.loadFS(embedded, TemplateSourceEmbedded); err != nil {
+		return nil, fmt.Errorf("failed to load embedded templates: %w", err)
+	}
+
+	for _, root := range []struct {
+		dir    string
+		source TemplateSource
+	}{
+		{o.userTemplatesDir, TemplateSourceUser},
+		{o.projectTemplatesDir, TemplateSourceProject},
+	} {
+		if root.dir == "" {
+			continue
+		}
+		if _, err := os.Stat(root.dir); err != nil {
+			// An unconfigured or not-yet-created custom template root is not
+			// an error; there is simply nothing to shadow embedded templates.
+			continue
+		}
+		if err := e.loadFS(os.DirFS(root.dir), root.source); err != nil {
+			return nil, fmt.Errorf("failed to load %s templates from %s: %w", root.source, root.dir, err)
+		}
+	}
+
+	return e, nil
+}
+
+// loadFS discovers and parses templates for every item type within fsys,
+// recording source as the origin of each template found.
+func (e *Engine) loadFS(fsys fs.FS, source TemplateSource) error {
+	for itemType, glob := range templateGlobs {
+		matches, err := fs.Glob(fsys, glob)
+		if err != nil {
+			return fmt.Errorf("failed to glob %s templates: %w", itemType, err)
+		}
+
+		for _, match := range matches {
+			name := templateNameFromMatch(itemType, match)
+
+			content, err := fs.ReadFile(fsys, match)
+			if err != nil {
+				return fmt.Errorf("failed to read template %s: %w", match, err)
+			}
+
+			body, when := extractWhenConstraint(string(content))
+
+			tmpl, err := template.New(name).Funcs(templateFuncMap()).Parse(body)
+			if err != nil {
+				return fmt.Errorf("failed to parse template %s: %w", match, err)
+			}
+
+			schemaPath := schemaPathFromMatch(itemType, match, name)
+			schema, err := loadSchema(fsys, schemaPath)
+			if err != nil {
+				return fmt.Errorf("failed to load schema for template %s: %w", name, err)
+			}
+
+			rawSchema, err := fs.ReadFile(fsys, schemaPath)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to read schema %s: %w", schemaPath, err)
+			}
+
+			if e.templates[itemType] == nil {
+				e.templates[itemType] = map[string]*template.Template{}
+				e.sources[itemType] = map[string]TemplateSource{}
+				e.schemas[itemType] = map[string]*Schema{}
+				e.rawTemplates[itemType] = map[string]string{}
+				e.rawSchemas[itemType] = map[string]string{}
+				e.constraints[itemType] = map[string]string{}
+			}
+			if _, exists := e.templates[itemType][name]; !exists {
+				e.templateNames[itemType] = append(e.templateNames[itemType], name)
+			}
+			e.templates[itemType][name] = tmpl
+			e.sources[itemType][name] = source
+			e.schemas[itemType][name] = schema
+			e.rawTemplates[itemType][name] = string(content)
+			e.rawSchemas[itemType][name] = string(rawSchema)
+			if when != "" {
+				e.constraints[itemType][name] = when
+			} else {
+				delete(e.constraints[itemType], name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// schemaPathFromMatch returns the path of the optional schema file that
+// accompanies a template, e.g. "skills/coding/SKILL.md.tmpl" ->
+// "skills/coding/schema.yaml" and "agents/foo.md.tmpl" -> "agents/foo.schema.yaml".
+func schemaPathFromMatch(itemType ItemType, match, name string) string {
+	dir := path.Dir(match)
+	if itemType == ItemTypeSkill {
+		return path.Join(dir, schemaFileName)
+	}
+	return path.Join(dir, name+".schema.yaml")
+}
+
+// loadSchema reads and parses the schema file at schemaPath, returning nil
+// if it doesn't exist. A template without a schema.yaml takes no declared
+// parameters; any --set values passed to it are used as-is.
+func loadSchema(fsys fs.FS, schemaPath string) (*Schema, error) {
+	content, err := fs.ReadFile(fsys, schemaPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read schema %s: %w", schemaPath, err)
+	}
+
+	return parseSchema(content)
+}
+
+// templateNameFromMatch extracts the template name from its path within a
+// template root, e.g. "skills/coding/SKILL.md.tmpl" -> "coding".
+func templateNameFromMatch(itemType ItemType, match string) string {
+	switch itemType {
+	case ItemTypeSkill:
+		return path.Base(path.Dir(match))
+	default:
+		base := path.Base(match)
+		return base[:len(base)-len(".md.tmpl")]
+	}
+}
+
+// Generate renders the named template for itemType with no parameter values.
+func (e *Engine) Generate(itemType ItemType, name string) (string, error) {
+	return e.GenerateWithParams(itemType, name, nil)
+}
+
+// GenerateWithParams renders the named template for itemType, validating
+// values against the template's schema.yaml (if any) and exposing the
+// result to the template as .Params.<name>.
+func (e *Engine) GenerateWithParams(itemType ItemType, name string, values map[string]any) (string, error) {
+	templates, ok := e.templates[itemType]
+	if !ok {
+		return "", fmt.Errorf("no templates found for type: %s", itemType)
+	}
+
+	tmpl, ok := templates[name]
+	if !ok {
+		return "", fmt.Errorf("template %s not found for type %s", name, itemType)
+	}
+
+	params, err := validateParams(e.schemas[itemType][name], values)
+	if err != nil {
+		return "", fmt.Errorf("invalid parameters for template %s: %w", name, err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, templateContext{Params: params}); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// Schema returns the declared parameters for the named template, or nil if
+// it has no schema.yaml.
+func (e *Engine) Schema(itemType ItemType, name string) *Schema {
+	return e.schemas[itemType][name]
+}
+
+// RawTemplate returns the unparsed source of the named template and its
+// schema.yaml (empty if it has none), for forking via `generator <kind> new
+// --from`. ok is false if no such template was found.
+func (e *Engine) RawTemplate(itemType ItemType, name string) (templateSource, schemaSource string, ok bool) {
+	tmpl, exists := e.rawTemplates[itemType][name]
+	if !exists {
+		return "", "", false
+	}
+	return tmpl, e.rawSchemas[itemType][name], true
+}
+
+// List returns the names of templates available for itemType.
+func (e *Engine) List(itemType ItemType) []string {
+	return e.templateNames[itemType]
+}
+
+// ListInfo returns the names of templates available for itemType, annotated
+// with the root each one was discovered in and whether it matches tags.
+func (e *Engine) ListInfo(itemType ItemType, tags map[string]bool) []TemplateInfo {
+	names := e.templateNames[itemType]
+	infos := make([]TemplateInfo, 0, len(names))
+	for _, name := range names {
+		infos = append(infos, TemplateInfo{
+			Name:    name,
+			Source:  e.sources[itemType][name],
+			Matches: e.Match(itemType, name, tags),
+		})
+	}
+	return infos
+}
+
+// Match reports whether the named template's "when:" constraint (if any) is
+// satisfied by tags. A template with no "when:" always matches. Semantics
+// mirror Go's build-constraint matcher restricted to a single line: comma is
+// AND, a "!" prefix is NOT, and a tag absent from the map defaults to false.
+func (e *Engine) Match(itemType ItemType, name string, tags map[string]bool) bool {
+	when, ok := e.constraints[itemType][name]
+	if !ok {
+		return true
+	}
+	return matchConstraint(when, tags)
+}
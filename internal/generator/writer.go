@@ -0,0 +1,174 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+// Writer handles writing generated content to files or stdout.
+type Writer struct {
+	config *Config
+	fs     afero.Fs
+	stdin  io.Reader
+	stdout io.Writer
+	now    func() time.Time
+
+	// overwriteAll is set once the user picks "all" in an interactive
+	// conflict prompt, so later conflicts in the same run skip the prompt.
+	overwriteAll bool
+}
+
+// NewWriter creates a new Writer with the given configuration, backed by the
+// real filesystem and the process's stdin/stdout.
+func NewWriter(config *Config) *Writer {
+	return NewWriterWithFs(config, afero.NewOsFs())
+}
+
+// NewWriterWithFs creates a new Writer with the given configuration and
+// filesystem. Tests can pass afero.NewMemMapFs() to exercise file conflict
+// and permission behavior without touching disk.
+func NewWriterWithFs(config *Config, fs afero.Fs) *Writer {
+	return &Writer{
+		config: config,
+		fs:     fs,
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		now:    time.Now,
+	}
+}
+
+// Write writes the generated content to the appropriate location, applying
+// the configured ConflictPolicy if a file already exists there.
+func (w *Writer) Write(itemType ItemType, name string, content string) error {
+	if w.config.DryRun {
+		fmt.Fprintln(w.stdout, content)
+		return nil
+	}
+
+	outputPath, err := w.GetOutputPath(itemType, name)
+	if err != nil {
+		return fmt.Errorf("failed to get output path: %w", err)
+	}
+
+	mode := os.FileMode(0644)
+
+	existingInfo, err := w.fs.Stat(outputPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check existing file %s: %w", outputPath, err)
+	}
+
+	if err == nil {
+		mode = existingInfo.Mode().Perm()
+
+		existing, err := afero.ReadFile(w.fs, outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to read existing file %s: %w", outputPath, err)
+		}
+
+		skip, err := w.resolveConflict(outputPath, string(existing), content)
+		if err != nil {
+			return err
+		}
+		if skip {
+			fmt.Fprintf(w.stdout, "skipped %s (already exists)\n", outputPath)
+			return nil
+		}
+	}
+
+	parentDir := filepath.Dir(outputPath)
+	if err := w.fs.MkdirAll(parentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory %s: %w", parentDir, err)
+	}
+
+	if err := afero.WriteFile(w.fs, outputPath, []byte(content), mode); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", outputPath, err)
+	}
+
+	return nil
+}
+
+// resolveConflict applies the configured ConflictPolicy to an existing file,
+// reporting whether the write should be skipped entirely. It may rename the
+// existing file (ConflictPolicyBackup) or return ErrAborted.
+func (w *Writer) resolveConflict(outputPath, existing, updated string) (skip bool, err error) {
+	if w.config.ShowDiff {
+		if err := printUnifiedDiff(w.stdout, outputPath, existing, updated); err != nil {
+			return false, err
+		}
+	}
+
+	policy := w.config.ConflictPolicy
+	if policy == "" {
+		policy = ConflictPolicyOverwrite
+	}
+
+	switch policy {
+	case ConflictPolicyOverwrite:
+		return false, nil
+
+	case ConflictPolicySkip:
+		return true, nil
+
+	case ConflictPolicyFail:
+		return false, fmt.Errorf("%s already exists", outputPath)
+
+	case ConflictPolicyBackup:
+		backup := backupPath(outputPath, w.now())
+		if err := w.fs.Rename(outputPath, backup); err != nil {
+			return false, fmt.Errorf("failed to back up %s: %w", outputPath, err)
+		}
+		return false, nil
+
+	case ConflictPolicyPrompt:
+		if w.overwriteAll {
+			return false, nil
+		}
+
+		action, err := promptConflict(w.stdin, w.stdout, outputPath, existing, updated)
+		if err != nil {
+			return false, err
+		}
+
+		switch action {
+		case conflictActionOverwrite:
+			return false, nil
+		case conflictActionOverwriteAll:
+			w.overwriteAll = true
+			return false, nil
+		case conflictActionSkip:
+			return true, nil
+		default:
+			return false, ErrAborted
+		}
+
+	default:
+		return false, fmt.Errorf("unknown conflict policy: %s", policy)
+	}
+}
+
+// GetOutputPath returns the output path for the given item type and name.
+func (w *Writer) GetOutputPath(itemType ItemType, name string) (string, error) {
+	outputDir, err := expandHomeDir(w.config.OutputDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand home directory: %w", err)
+	}
+
+	var relativePath string
+	switch itemType {
+	case ItemTypeSkill:
+		relativePath = filepath.Join("skills", name, "SKILL.md")
+	case ItemTypeAgent:
+		relativePath = filepath.Join("agents", fmt.Sprintf("%s.md", name))
+	case ItemTypeCommand:
+		relativePath = filepath.Join("commands", fmt.Sprintf("%s.md", name))
+	default:
+		return "", fmt.Errorf("unknown item type: %s", itemType)
+	}
+
+	return filepath.Join(outputDir, relativePath), nil
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -153,10 +154,10 @@ func TestWriter_Write_DryRun(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			writer := NewWriter(&Config{
+			writer := NewWriterWithFs(&Config{
 				OutputDir: "/test/output",
 				DryRun:    true,
-			})
+			}, afero.NewMemMapFs())
 
 			err := writer.Write(tt.itemType, tt.itemName, tt.content)
 
@@ -194,11 +195,11 @@ func TestWriter_Write_ActualFileWriting(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tempDir := t.TempDir()
-			writer := NewWriter(&Config{
-				OutputDir: tempDir,
+			fs := afero.NewMemMapFs()
+			writer := NewWriterWithFs(&Config{
+				OutputDir: "/output",
 				DryRun:    false,
-			})
+			}, fs)
 
 			err := writer.Write(tt.itemType, tt.itemName, tt.content)
 			require.NoError(t, err)
@@ -207,17 +208,12 @@ func TestWriter_Write_ActualFileWriting(t *testing.T) {
 			outputPath, err := writer.GetOutputPath(tt.itemType, tt.itemName)
 			require.NoError(t, err)
 
-			// Check file exists
-			_, err = os.Stat(outputPath)
-			require.NoError(t, err)
-
-			// Verify content
-			gotContent, err := os.ReadFile(outputPath)
+			gotContent, err := afero.ReadFile(fs, outputPath)
 			require.NoError(t, err)
 			assert.Equal(t, tt.content, string(gotContent))
 
 			// Verify file permissions
-			info, err := os.Stat(outputPath)
+			info, err := fs.Stat(outputPath)
 			require.NoError(t, err)
 			assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
 		})
@@ -241,26 +237,20 @@ func TestWriter_Write_CreatesParentDirectories(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tempDir := t.TempDir()
-			writer := NewWriter(&Config{
-				OutputDir: tempDir,
+			fs := afero.NewMemMapFs()
+			writer := NewWriterWithFs(&Config{
+				OutputDir: "/output",
 				DryRun:    false,
-			})
+			}, fs)
 
 			err := writer.Write(tt.itemType, tt.itemName, tt.content)
 			require.NoError(t, err)
 
-			// Verify file was written
 			outputPath, err := writer.GetOutputPath(tt.itemType, tt.itemName)
 			require.NoError(t, err)
 
-			// Check file exists
-			_, err = os.Stat(outputPath)
-			require.NoError(t, err)
-
-			// Verify parent directory has correct permissions
 			parentDir := filepath.Dir(outputPath)
-			info, err := os.Stat(parentDir)
+			info, err := fs.Stat(parentDir)
 			require.NoError(t, err)
 			assert.True(t, info.IsDir())
 			assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
@@ -268,6 +258,193 @@ func TestWriter_Write_CreatesParentDirectories(t *testing.T) {
 	}
 }
 
+func TestWriter_Write_ReadOnlyFsReturnsError(t *testing.T) {
+	base := afero.NewMemMapFs()
+	require.NoError(t, base.MkdirAll("/output/skills/test-skill", 0755))
+	fs := afero.NewReadOnlyFs(base)
+
+	writer := NewWriterWithFs(&Config{
+		OutputDir: "/output",
+		DryRun:    false,
+	}, fs)
+
+	err := writer.Write(ItemTypeSkill, "test-skill", "content")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create parent directory")
+}
+
+func TestWriter_Write_ConflictPolicies(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       ConflictPolicy
+		wantContent  string
+		wantSkipMsg  bool
+		wantErrMsg   string
+		wantBackedUp bool
+	}{
+		{
+			name:        "overwrite replaces the existing file",
+			policy:      ConflictPolicyOverwrite,
+			wantContent: "new content",
+		},
+		{
+			name:        "empty policy defaults to overwrite",
+			policy:      "",
+			wantContent: "new content",
+		},
+		{
+			name:        "skip leaves the existing file untouched",
+			policy:      ConflictPolicySkip,
+			wantContent: "old content",
+			wantSkipMsg: true,
+		},
+		{
+			name:       "fail returns an error and leaves the file untouched",
+			policy:     ConflictPolicyFail,
+			wantErrMsg: "already exists",
+		},
+		{
+			name:         "backup renames the existing file before writing",
+			policy:       ConflictPolicyBackup,
+			wantContent:  "new content",
+			wantBackedUp: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "/output/agents/test-agent.md", []byte("old content"), 0644))
+
+			var stdout strings.Builder
+			writer := NewWriterWithFs(&Config{
+				OutputDir:      "/output",
+				ConflictPolicy: tt.policy,
+			}, fs)
+			writer.stdout = &stdout
+
+			err := writer.Write(ItemTypeAgent, "test-agent", "new content")
+
+			if tt.wantErrMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrMsg)
+
+				got, readErr := afero.ReadFile(fs, "/output/agents/test-agent.md")
+				require.NoError(t, readErr)
+				assert.Equal(t, "old content", string(got))
+				return
+			}
+
+			require.NoError(t, err)
+
+			got, readErr := afero.ReadFile(fs, "/output/agents/test-agent.md")
+			require.NoError(t, readErr)
+			assert.Equal(t, tt.wantContent, string(got))
+
+			if tt.wantSkipMsg {
+				assert.Contains(t, stdout.String(), "skipped")
+			}
+
+			if tt.wantBackedUp {
+				matches, err := afero.Glob(fs, "/output/agents/test-agent.md.bak.*")
+				require.NoError(t, err)
+				require.Len(t, matches, 1)
+
+				backedUp, err := afero.ReadFile(fs, matches[0])
+				require.NoError(t, err)
+				assert.Equal(t, "old content", string(backedUp))
+			}
+		})
+	}
+}
+
+func TestWriter_Write_PreservesExistingFileMode(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/output/agents/test-agent.md", []byte("old content"), 0600))
+
+	writer := NewWriterWithFs(&Config{OutputDir: "/output", ConflictPolicy: ConflictPolicyOverwrite}, fs)
+	require.NoError(t, writer.Write(ItemTypeAgent, "test-agent", "new content"))
+
+	info, err := fs.Stat("/output/agents/test-agent.md")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestWriter_Write_PromptPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantContent string
+		wantErr     error
+	}{
+		{
+			name:        "yes overwrites",
+			input:       "y\n",
+			wantContent: "new content",
+		},
+		{
+			name:        "no skips",
+			input:       "n\n",
+			wantContent: "old content",
+		},
+		{
+			name:        "diff then yes overwrites",
+			input:       "d\ny\n",
+			wantContent: "new content",
+		},
+		{
+			name:    "abort returns ErrAborted",
+			input:   "b\n",
+			wantErr: ErrAborted,
+		},
+		{
+			name:    "eof aborts",
+			input:   "",
+			wantErr: ErrAborted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "/output/agents/test-agent.md", []byte("old content"), 0644))
+
+			writer := NewWriterWithFs(&Config{OutputDir: "/output", ConflictPolicy: ConflictPolicyPrompt}, fs)
+			writer.stdin = strings.NewReader(tt.input)
+			writer.stdout = &strings.Builder{}
+
+			err := writer.Write(ItemTypeAgent, "test-agent", "new content")
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got, readErr := afero.ReadFile(fs, "/output/agents/test-agent.md")
+			require.NoError(t, readErr)
+			assert.Equal(t, tt.wantContent, string(got))
+		})
+	}
+}
+
+func TestWriter_Write_PromptPolicy_OverwriteAllAppliesToLaterConflicts(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/output/agents/one.md", []byte("old one"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/output/agents/two.md", []byte("old two"), 0644))
+
+	writer := NewWriterWithFs(&Config{OutputDir: "/output", ConflictPolicy: ConflictPolicyPrompt}, fs)
+	writer.stdin = strings.NewReader("a\n")
+	writer.stdout = &strings.Builder{}
+
+	require.NoError(t, writer.Write(ItemTypeAgent, "one", "new one"))
+	require.NoError(t, writer.Write(ItemTypeAgent, "two", "new two"))
+
+	got, err := afero.ReadFile(fs, "/output/agents/two.md")
+	require.NoError(t, err)
+	assert.Equal(t, "new two", string(got))
+}
+
 func Test_expandHomeDir(t *testing.T) {
 	homeDir, err := os.UserHomeDir()
 	require.NoError(t, err)
@@ -301,9 +478,7 @@ func Test_expandHomeDir(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			writer := NewWriter(&Config{})
-
-			got, err := writer.expandHomeDir(tt.path)
+			got, err := expandHomeDir(tt.path)
 
 			require.NoError(t, err)
 			// Normalize paths for comparison on different platforms